@@ -0,0 +1,3 @@
+package example
+
+//go:generate protoc -I .. -I serrors/v1=../serrorspb --go-serrors_out=.. --go-serrors_opt=paths=source_relative ../example/example.proto
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+
+	"github.com/sanyuanya/errors/cmd/protoc-gen-go-serrors/serrorspb"
+)
+
+// enumValueOptions builds the EnumValueOptions a real protoc run would attach
+// for the [(serrors.v1.code) = ..., (serrors.v1.reason) = ...] annotations in
+// example.proto.
+func enumValueOptions(code int32, reason string) *descriptorpb.EnumValueOptions {
+	opts := &descriptorpb.EnumValueOptions{}
+	if code != 0 {
+		proto.SetExtension(opts, serrorspb.E_Code, code)
+	}
+	if reason != "" {
+		proto.SetExtension(opts, serrorspb.E_Reason, reason)
+	}
+	return opts
+}
+
+// exampleFileDescriptorProto reconstructs the FileDescriptorProto protoc
+// would produce for example/example.proto, without needing protoc on PATH.
+func exampleFileDescriptorProto() *descriptorpb.FileDescriptorProto {
+	return &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("example/example.proto"),
+		Package:    proto.String("example"),
+		Dependency: []string{"serrors/v1/errors.proto"},
+		Syntax:     proto.String("proto3"),
+		Options: &descriptorpb.FileOptions{
+			GoPackage: proto.String("github.com/sanyuanya/errors/cmd/protoc-gen-go-serrors/example;example"),
+		},
+		EnumType: []*descriptorpb.EnumDescriptorProto{
+			{
+				Name: proto.String("ErrorReason"),
+				Value: []*descriptorpb.EnumValueDescriptorProto{
+					{
+						Name:    proto.String("USER_NOT_FOUND"),
+						Number:  proto.Int32(0),
+						Options: enumValueOptions(404, ""),
+					},
+					{
+						Name:    proto.String("USER_FORBIDDEN"),
+						Number:  proto.Int32(1),
+						Options: enumValueOptions(403, "FORBIDDEN"),
+					},
+				},
+			},
+		},
+	}
+}
+
+// TestGenerateFileMatchesGoldenFixture feeds the plugin the descriptor it
+// would receive for example.proto and checks the output against the
+// example_serrors.pb.go committed alongside gen.go, so a regression in
+// generateFile is caught without needing protoc installed.
+func TestGenerateFileMatchesGoldenFixture(t *testing.T) {
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"example/example.proto"},
+		Parameter:      proto.String("paths=source_relative"),
+		ProtoFile: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto),
+			protodesc.ToFileDescriptorProto(serrorspb.File_serrors_v1_errors_proto),
+			exampleFileDescriptorProto(),
+		},
+	}
+
+	gen, err := protogen.Options{}.New(req)
+	if err != nil {
+		t.Fatalf("protogen.Options.New: %v", err)
+	}
+	for _, f := range gen.Files {
+		if f.Generate {
+			generateFile(gen, f)
+		}
+	}
+	resp := gen.Response()
+	if resp.Error != nil {
+		t.Fatalf("generator reported an error: %s", resp.GetError())
+	}
+
+	const wantName = "example/example_serrors.pb.go"
+	var got []byte
+	for _, f := range resp.File {
+		if f.GetName() == wantName {
+			got = []byte(f.GetContent())
+		}
+	}
+	if got == nil {
+		t.Fatalf("generator did not produce %s", wantName)
+	}
+
+	want, err := os.ReadFile(filepath.Join("example", "example_serrors.pb.go"))
+	if err != nil {
+		t.Fatalf("reading golden fixture: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Fatalf("generated output does not match the committed fixture.\n--- got ---\n%s\n--- want ---\n%s", got, want)
+	}
+}
@@ -0,0 +1,80 @@
+package serrors
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func TestDetailsRoundTripThroughGRPCStatus(t *testing.T) {
+	fields := []FieldViolation{{Field: "email", Description: "must not be empty"}}
+	links := []HelpLink{{Description: "docs", Url: "https://example.com/docs"}}
+
+	top := New(400, "bad request").
+		WithRetryInfo(5 * time.Second).
+		WithBadRequest(fields...).
+		WithQuotaFailure(&errdetails.QuotaFailure_Violation{Subject: "user:42", Description: "rate limited"}).
+		WithPreconditionFailure(&errdetails.PreconditionFailure_Violation{Type: "TOS", Subject: "user:42", Description: "must accept terms"}).
+		WithHelp(links...).
+		WithLocalizedMessage("en-US", "Bad request").
+		WithRequestInfo("req-123", "serving-data")
+
+	got := FromError(grpcRoundTrip(top))
+
+	if got.RetryInfo() == nil || got.RetryInfo().GetRetryDelay().AsDuration() != 5*time.Second {
+		t.Fatalf("RetryInfo round-trip failed: %+v", got.RetryInfo())
+	}
+	if br := got.BadRequest(); br == nil || len(br.GetFieldViolations()) != 1 ||
+		br.GetFieldViolations()[0].GetField() != "email" ||
+		br.GetFieldViolations()[0].GetDescription() != "must not be empty" {
+		t.Fatalf("BadRequest round-trip failed: %+v", br)
+	}
+	if qf := got.QuotaFailure(); qf == nil || len(qf.GetViolations()) != 1 ||
+		qf.GetViolations()[0].GetSubject() != "user:42" ||
+		qf.GetViolations()[0].GetDescription() != "rate limited" {
+		t.Fatalf("QuotaFailure round-trip failed: %+v", qf)
+	}
+	if pf := got.PreconditionFailure(); pf == nil || len(pf.GetViolations()) != 1 ||
+		pf.GetViolations()[0].GetType() != "TOS" ||
+		pf.GetViolations()[0].GetSubject() != "user:42" {
+		t.Fatalf("PreconditionFailure round-trip failed: %+v", pf)
+	}
+	if h := got.Help(); h == nil || len(h.GetLinks()) != 1 ||
+		h.GetLinks()[0].GetDescription() != "docs" ||
+		h.GetLinks()[0].GetUrl() != "https://example.com/docs" {
+		t.Fatalf("Help round-trip failed: %+v", h)
+	}
+	if lm := got.LocalizedMessage(); lm == nil || lm.GetLocale() != "en-US" || lm.GetMessage() != "Bad request" {
+		t.Fatalf("LocalizedMessage round-trip failed: %+v", lm)
+	}
+	if ri := got.RequestInfo(); ri == nil || ri.GetRequestId() != "req-123" || ri.GetServingData() != "serving-data" {
+		t.Fatalf("RequestInfo round-trip failed: %+v", ri)
+	}
+}
+
+func TestDetailsAccessorsNilWhenUnset(t *testing.T) {
+	err := New(500, "boom")
+
+	if err.RetryInfo() != nil {
+		t.Fatal("RetryInfo should be nil when never set")
+	}
+	if err.BadRequest() != nil {
+		t.Fatal("BadRequest should be nil when never set")
+	}
+	if err.QuotaFailure() != nil {
+		t.Fatal("QuotaFailure should be nil when never set")
+	}
+	if err.PreconditionFailure() != nil {
+		t.Fatal("PreconditionFailure should be nil when never set")
+	}
+	if err.Help() != nil {
+		t.Fatal("Help should be nil when never set")
+	}
+	if err.LocalizedMessage() != nil {
+		t.Fatal("LocalizedMessage should be nil when never set")
+	}
+	if err.RequestInfo() != nil {
+		t.Fatal("RequestInfo should be nil when never set")
+	}
+}
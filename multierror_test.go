@@ -0,0 +1,117 @@
+package serrors
+
+import (
+	stderrors "errors"
+	"strings"
+	"testing"
+)
+
+func TestJoinUnwrapsEveryBranch(t *testing.T) {
+	a := New(500, "backend a down")
+	b := New(503, "backend b down")
+	plain := stderrors.New("backend c down")
+
+	joined := Join(a, b, plain)
+
+	if joined.Errcode != UnknownCode {
+		t.Fatalf("Join result got code %d, want UnknownCode", joined.Errcode)
+	}
+	if !stderrors.Is(joined, a) || !stderrors.Is(joined, b) || !stderrors.Is(joined, plain) {
+		t.Fatal("errors.Is should find every joined branch")
+	}
+
+	var target *Error
+	if !stderrors.As(joined, &target) || target.Errcode != 500 {
+		t.Fatalf("errors.As should match the joined *Error itself (UnknownCode), got %+v", target)
+	}
+}
+
+func TestJoinDropsNilsAndEmptyIsNil(t *testing.T) {
+	if Join() != nil {
+		t.Fatal("Join() with no errors should be nil")
+	}
+	if Join(nil, nil) != nil {
+		t.Fatal("Join of only nils should be nil")
+	}
+	joined := Join(nil, New(500, "boom"), nil)
+	if len(joined.causes) != 1 {
+		t.Fatalf("Join should drop nils, got %d causes", len(joined.causes))
+	}
+}
+
+func TestErrorStringIndentsEachCause(t *testing.T) {
+	top := New(500, "internal error").WithCauses(
+		New(404, "user not found"),
+		stderrors.New("timeout"),
+	)
+
+	s := top.Error()
+	lines := strings.Split(s, "\n")
+	if lines[0] != "errcode: code = 500 errmsg = internal error result = map[]" {
+		t.Fatalf("unexpected top line: %q", lines[0])
+	}
+	var causeLines int
+	for _, line := range lines[1:] {
+		if !strings.HasPrefix(line, "\t") {
+			t.Fatalf("expected every cause line to be indented, got %q", line)
+		}
+		causeLines++
+	}
+	if causeLines != 2 {
+		t.Fatalf("expected one rendered line per cause, got %d", causeLines)
+	}
+	if !strings.Contains(s, "user not found") || !strings.Contains(s, "timeout") {
+		t.Fatalf("expected both causes to appear in the message, got %q", s)
+	}
+}
+
+func TestWithCausesReplacesPriorCausesAndRecapturesStack(t *testing.T) {
+	first := stderrors.New("first")
+	base := New(500, "boom").WithCause(first)
+	firstFrame := base.StackTrace()[0]
+
+	replaced := base.WithCauses(stderrors.New("second"), stderrors.New("third"))
+
+	if len(replaced.causes) != 2 {
+		t.Fatalf("expected the prior single cause to be replaced by two, got %d", len(replaced.causes))
+	}
+	if stderrors.Is(replaced, first) {
+		t.Fatal("the cause from the first WithCause call should no longer be reachable")
+	}
+	if len(replaced.StackTrace()) == 0 {
+		t.Fatal("WithCauses should capture a stack")
+	}
+	if replaced.StackTrace()[0].Line == firstFrame.Line {
+		t.Fatal("WithCauses should recapture a fresh stack, not reuse the prior WithCause's")
+	}
+}
+
+func TestGRPCStatusFromErrorMultiCause(t *testing.T) {
+	joined := New(500, "fan-out failed").WithCauses(
+		New(404, "user not found"),
+		New(503, "payments unavailable"),
+		stderrors.New("cache miss"),
+	)
+
+	got := FromError(grpcRoundTrip(joined))
+
+	if len(got.causes) != 3 {
+		t.Fatalf("expected 3 reconstructed causes, got %d", len(got.causes))
+	}
+	wantCodes := map[int32]bool{404: false, 503: false, int32(UnknownCode): false}
+	for _, c := range got.causes {
+		ce, ok := c.(*Error)
+		if !ok {
+			t.Fatalf("cause is %T, want *Error", c)
+		}
+		if _, known := wantCodes[ce.Errcode]; !known {
+			t.Fatalf("unexpected reconstructed cause code %d", ce.Errcode)
+		}
+		wantCodes[ce.Errcode] = true
+	}
+	for code, seen := range wantCodes {
+		if !seen {
+			t.Fatalf("missing reconstructed cause with code %d", code)
+		}
+	}
+}
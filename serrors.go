@@ -1,12 +1,20 @@
 package serrors
 
 import (
-	"serrors"
+	"errors"
 	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	httpstatus "github.com/go-kratos/kratos/v2/transport/http/status"
+	"github.com/golang/protobuf/proto"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 const (
@@ -18,20 +26,180 @@ const (
 	SupportPackageIsVersion1 = true
 )
 
+// FieldViolation describes a single bad request field, as reported by
+// WithBadRequest.
+type FieldViolation = errdetails.BadRequest_FieldViolation
+
+// HelpLink is a single documentation link, as reported by WithHelp.
+type HelpLink = errdetails.Help_Link
+
+// Status carries the basic fields of a serrors error: an application-level
+// code, a message, and free-form metadata. It is embedded in Error so that
+// Errcode, Errmsg and Result are promoted onto every *Error value.
+type Status struct {
+	Errcode int32
+	Errmsg  string
+	Result  map[string]string
+}
+
 // Error is a status error.
 type Error struct {
 	Status
-	cause error
+	causes []error
+	stack  []uintptr
+
+	retryInfo           *errdetails.RetryInfo
+	badRequest          *errdetails.BadRequest
+	quotaFailure        *errdetails.QuotaFailure
+	preconditionFailure *errdetails.PreconditionFailure
+	help                *errdetails.Help
+	localizedMessage    *errdetails.LocalizedMessage
+	requestInfo         *errdetails.RequestInfo
 }
 
 func (e *Error) Error() string {
-	return fmt.Sprintf("errcode: code = %d errmsg = %s result = %v cause = %v", e.Errcode, e.Errmsg, e.Result)
+	var b strings.Builder
+	fmt.Fprintf(&b, "errcode: code = %d errmsg = %s result = %v", e.Errcode, e.Errmsg, e.Result)
+	for _, cause := range e.causes {
+		for i, line := range strings.Split(cause.Error(), "\n") {
+			if i == 0 {
+				b.WriteString("\n\tcause: ")
+			} else {
+				b.WriteString("\n\t")
+			}
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
+
+// maxStackDepth bounds how many frames New, Newf, Errorf and WithCause will
+// record when stack capture is enabled.
+const maxStackDepth = 32
+
+// captureStackEnabled controls whether errors record a stack trace on
+// creation. It defaults to enabled; call SetCaptureStack(false) to disable
+// it for zero overhead in production.
+var captureStackEnabled int32 = 1
+
+// SetCaptureStack enables or disables stack capture for errors created
+// afterwards. It is safe to call concurrently.
+func SetCaptureStack(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&captureStackEnabled, v)
 }
 
-// Unwrap provides compatibility for Go 1.13 error chains.
-func (e *Error) Unwrap() error { return e.cause }
+// pkgFile is the source file of this package, used to filter its own frames
+// out of a captured stack trace.
+var pkgFile = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return file
+}()
 
-// Is matches each error in the chain with the target value.
+// captureStack records the current call stack on e, unless capture is
+// disabled or e already carries one (so cloning an error never loses its
+// original stack).
+func (e *Error) captureStack(skip int) {
+	if atomic.LoadInt32(&captureStackEnabled) == 0 || e.stack != nil {
+		return
+	}
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip+2, pcs)
+	e.stack = pcs[:n]
+}
+
+// Frame describes a single entry of a captured stack trace.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// StackTrace returns the frames captured when e was created, filtering out
+// frames from inside this package. It returns nil if e has no captured
+// stack (stack capture was disabled via SetCaptureStack, or e predates it).
+func (e *Error) StackTrace() []Frame {
+	if len(e.stack) == 0 {
+		return nil
+	}
+	frames := runtime.CallersFrames(e.stack)
+	var out []Frame
+	for {
+		f, more := frames.Next()
+		if f.File != pkgFile {
+			out = append(out, Frame{Function: f.Function, File: f.File, Line: f.Line})
+		}
+		if !more {
+			break
+		}
+	}
+	return out
+}
+
+// Format implements fmt.Formatter. %+v prints the error message followed by
+// its stack trace, each frame as a function line followed by an indented
+// file:line line. %v and %s print just the message and %q prints it quoted;
+// any other verb prints nothing.
+func (e *Error) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if f.Flag('+') {
+			io.WriteString(f, e.Error())
+			for _, fr := range e.StackTrace() {
+				fmt.Fprintf(f, "\n\t%s\n\t\t%s:%d", fr.Function, fr.File, fr.Line)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(f, e.Error())
+	case 'q':
+		fmt.Fprintf(f, "%q", e.Error())
+	}
+}
+
+// unwrapAll returns err's immediate causes, regardless of whether it
+// implements the single-cause Unwrap() error or the multi-cause
+// Unwrap() []error interface.
+func unwrapAll(err error) []error {
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		return u.Unwrap()
+	case interface{ Unwrap() error }:
+		if next := u.Unwrap(); next != nil {
+			return []error{next}
+		}
+	}
+	return nil
+}
+
+// Cause returns the root cause of err, unwrapping it repeatedly until it
+// reaches an error with no further cause. For an err that unwraps to more
+// than one cause (see Join), the first one is followed.
+func Cause(err error) error {
+	for {
+		causes := unwrapAll(err)
+		if len(causes) == 0 {
+			return err
+		}
+		err = causes[0]
+	}
+}
+
+// Unwrap implements the Go 1.20 multi-error interface, so errors.Is and
+// errors.As traverse every cause attached with WithCause, WithCauses or
+// Join.
+func (e *Error) Unwrap() []error { return e.causes }
+
+// Is matches each error in the chain with the target value, by Errcode
+// alone: *Error has no field that survives Newf's message formatting other
+// than the code, so that is all Is can compare. One consequence, relevant to
+// protoc-gen-go-serrors, is that two enum values sharing an HTTP code make
+// their generated IsXxx predicates indistinguishable; see that package's
+// doc comment.
 func (e *Error) Is(err error) bool {
 	if se := new(Error); errors.As(err, &se) {
 		return se.Errcode == e.Errcode
@@ -41,11 +209,44 @@ func (e *Error) Is(err error) bool {
 
 // WithCause with the underlying cause of the error.
 func (e *Error) WithCause(cause error) *Error {
+	return e.WithCauses(cause)
+}
+
+// WithCauses attaches causes as the underlying causes of the error,
+// replacing any causes set by a previous WithCause/WithCauses/Join call.
+// errors.Is and errors.As traverse all of them, via Unwrap() []error.
+// WithCauses recaptures the stack trace at its own call site, so
+// StackTrace() points at where the error was wrapped, not where e was
+// originally created.
+func (e *Error) WithCauses(causes ...error) *Error {
 	err := Clone(e)
-	err.cause = cause
+	err.causes = causes
+	err.stack = nil
+	err.captureStack(1)
 	return err
 }
 
+// Join returns a *Error that aggregates errs as independent causes: it
+// implements Unwrap() []error so errors.Is/errors.As traverse every branch,
+// and its Error() string renders each cause on its own indented line. Join
+// mirrors the standard library's errors.Join, but returns a *Error so the
+// result still carries an HTTP code (UnknownCode, since no single cause is
+// authoritative) and can flow through GRPCStatus/FromError like any other
+// serrors error. Nil errors are dropped; Join returns nil if every err is
+// nil.
+func Join(errs ...error) *Error {
+	causes := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			causes = append(causes, err)
+		}
+	}
+	if len(causes) == 0 {
+		return nil
+	}
+	return New(UnknownCode, UnknownReason).WithCauses(causes...)
+}
+
 // WithMetadata with an MD formed by the mapping of key, value.
 func (e *Error) WithMetadata(md map[string]string) *Error {
 	err := Clone(e)
@@ -53,23 +254,211 @@ func (e *Error) WithMetadata(md map[string]string) *Error {
 	return err
 }
 
+// WithRetryInfo attaches a RetryInfo detail telling the client to back off
+// for the given delay before retrying.
+func (e *Error) WithRetryInfo(delay time.Duration) *Error {
+	err := Clone(e)
+	err.retryInfo = &errdetails.RetryInfo{RetryDelay: durationpb.New(delay)}
+	return err
+}
+
+// RetryInfo returns the RetryInfo detail attached by WithRetryInfo, or nil.
+func (e *Error) RetryInfo() *errdetails.RetryInfo { return e.retryInfo }
+
+// WithBadRequest attaches a BadRequest detail listing the offending fields.
+func (e *Error) WithBadRequest(fields ...FieldViolation) *Error {
+	err := Clone(e)
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(fields))
+	for i := range fields {
+		violations = append(violations, &fields[i])
+	}
+	err.badRequest = &errdetails.BadRequest{FieldViolations: violations}
+	return err
+}
+
+// BadRequest returns the BadRequest detail attached by WithBadRequest, or nil.
+func (e *Error) BadRequest() *errdetails.BadRequest { return e.badRequest }
+
+// WithQuotaFailure attaches a QuotaFailure detail listing the violated
+// quotas.
+func (e *Error) WithQuotaFailure(violations ...*errdetails.QuotaFailure_Violation) *Error {
+	err := Clone(e)
+	err.quotaFailure = &errdetails.QuotaFailure{Violations: violations}
+	return err
+}
+
+// QuotaFailure returns the QuotaFailure detail attached by WithQuotaFailure,
+// or nil.
+func (e *Error) QuotaFailure() *errdetails.QuotaFailure { return e.quotaFailure }
+
+// WithPreconditionFailure attaches a PreconditionFailure detail listing the
+// violated preconditions.
+func (e *Error) WithPreconditionFailure(violations ...*errdetails.PreconditionFailure_Violation) *Error {
+	err := Clone(e)
+	err.preconditionFailure = &errdetails.PreconditionFailure{Violations: violations}
+	return err
+}
+
+// PreconditionFailure returns the PreconditionFailure detail attached by
+// WithPreconditionFailure, or nil.
+func (e *Error) PreconditionFailure() *errdetails.PreconditionFailure { return e.preconditionFailure }
+
+// WithHelp attaches a Help detail with links the caller can follow to
+// resolve the error.
+func (e *Error) WithHelp(links ...HelpLink) *Error {
+	err := Clone(e)
+	ls := make([]*errdetails.Help_Link, 0, len(links))
+	for i := range links {
+		ls = append(ls, &links[i])
+	}
+	err.help = &errdetails.Help{Links: ls}
+	return err
+}
+
+// Help returns the Help detail attached by WithHelp, or nil.
+func (e *Error) Help() *errdetails.Help { return e.help }
+
+// WithLocalizedMessage attaches a LocalizedMessage detail carrying a message
+// translated into locale.
+func (e *Error) WithLocalizedMessage(locale, msg string) *Error {
+	err := Clone(e)
+	err.localizedMessage = &errdetails.LocalizedMessage{Locale: locale, Message: msg}
+	return err
+}
+
+// LocalizedMessage returns the LocalizedMessage detail attached by
+// WithLocalizedMessage, or nil.
+func (e *Error) LocalizedMessage() *errdetails.LocalizedMessage { return e.localizedMessage }
+
+// WithRequestInfo attaches a RequestInfo detail so clients can report id back
+// when asking for help with a failed request.
+func (e *Error) WithRequestInfo(id, servingData string) *Error {
+	err := Clone(e)
+	err.requestInfo = &errdetails.RequestInfo{RequestId: id, ServingData: servingData}
+	return err
+}
+
+// RequestInfo returns the RequestInfo detail attached by WithRequestInfo, or
+// nil.
+func (e *Error) RequestInfo() *errdetails.RequestInfo { return e.requestInfo }
+
+// chainDetailDomain marks a *structpb.Struct detail produced by GRPCStatus to
+// encode one node of a wrapped cause tree, so FromError can tell it apart
+// from details added by unrelated code.
+const chainDetailDomain = "serrors.chain"
+
+// causeDetail builds the wire representation for a single node of a cause
+// tree. parent is the index, within the flattened detail list, of this
+// node's parent, or -1 if it is attached directly to the top error. Causes
+// that are not themselves *Error are flattened to their error string under
+// UnknownCode.
+func causeDetail(err error, parent int) *structpb.Struct {
+	code := UnknownCode
+	msg := err.Error()
+	var result map[string]string
+	if se, ok := err.(*Error); ok {
+		code = int(se.Errcode)
+		msg = se.Errmsg
+		result = se.Result
+	}
+	fields := map[string]interface{}{
+		"domain":  chainDetailDomain,
+		"errcode": float64(code),
+		"errmsg":  msg,
+		"parent":  float64(parent),
+	}
+	if len(result) > 0 {
+		md := make(map[string]interface{}, len(result))
+		for k, v := range result {
+			md[k] = v
+		}
+		fields["result"] = md
+	}
+	st, _ := structpb.NewStruct(fields)
+	return st
+}
+
+// appendCauseDetails flattens causes (and their own causes, recursively)
+// into details, recording each node's parent index so errorFromCauseDetail
+// can rebuild the tree.
+func appendCauseDetails(causes []error, parent int, details *[]proto.Message, n *int) {
+	for _, cause := range causes {
+		idx := *n
+		*n++
+		*details = append(*details, causeDetail(cause, parent))
+		appendCauseDetails(unwrapAll(cause), idx, details, n)
+	}
+}
+
+// errorFromCauseDetail reverses causeDetail, rebuilding the *Error a node
+// describes along with its parent index. It reports false for structs that
+// are not chain details.
+func errorFromCauseDetail(st *structpb.Struct) (e *Error, parent int, ok bool) {
+	fs := st.GetFields()
+	if fs["domain"].GetStringValue() != chainDetailDomain {
+		return nil, 0, false
+	}
+	e = New(int(fs["errcode"].GetNumberValue()), fs["errmsg"].GetStringValue())
+	if md := fs["result"].GetStructValue(); md != nil {
+		result := make(map[string]string, len(md.GetFields()))
+		for k, v := range md.GetFields() {
+			result[k] = v.GetStringValue()
+		}
+		e.Result = result
+	}
+	return e, int(fs["parent"].GetNumberValue()), true
+}
+
 // GRPCStatus returns the Status represented by se.
+//
+// The outermost Status keeps e's own code and message, so clients that only
+// look at that part of the response are unaffected. If e wraps one or more
+// causes (via WithCause, WithCauses or Join), the whole cause tree is
+// flattened into additional details, letting FromError reconstruct it on the
+// other side of the call.
 func (e *Error) GRPCStatus() *status.Status {
-	s, _ := status.New(httpstatus.ToGRPCCode(int(e.Errcode)), e.Errmsg).
-		WithDetails(&errdetails.ErrorInfo{
+	details := []proto.Message{
+		&errdetails.ErrorInfo{
 			Metadata: e.Result,
-		})
+		},
+	}
+	if e.retryInfo != nil {
+		details = append(details, e.retryInfo)
+	}
+	if e.badRequest != nil {
+		details = append(details, e.badRequest)
+	}
+	if e.quotaFailure != nil {
+		details = append(details, e.quotaFailure)
+	}
+	if e.preconditionFailure != nil {
+		details = append(details, e.preconditionFailure)
+	}
+	if e.help != nil {
+		details = append(details, e.help)
+	}
+	if e.localizedMessage != nil {
+		details = append(details, e.localizedMessage)
+	}
+	if e.requestInfo != nil {
+		details = append(details, e.requestInfo)
+	}
+	n := 0
+	appendCauseDetails(e.causes, -1, &details, &n)
+	s, _ := status.New(httpstatus.ToGRPCCode(int(e.Errcode)), e.Errmsg).WithDetails(details...)
 	return s
 }
 
 // New returns an error object for the code, message.
 func New(code int, message string) *Error {
-	return &Error{
+	e := &Error{
 		Status: Status{
 			Errcode: int32(code),
 			Errmsg:  message,
 		},
 	}
+	e.captureStack(1)
+	return e
 }
 
 // Newf New(code fmt.Sprintf(format, a...))
@@ -91,6 +480,46 @@ func Code(err error) int {
 	return int(FromError(err).Errcode)
 }
 
+// sentinelMapping pairs a serrors sentinel with the stdlib or third-party
+// errors it stands in for, as declared through Register.
+type sentinelMapping struct {
+	sentinel *Error
+	aliases  []error
+}
+
+var sentinelMappings []sentinelMapping
+
+// Register declares that sentinel should be FromError's result whenever an
+// otherwise-unrecognised error matches one of aliases, following the
+// containerd/errdefs Resolve pattern: the match is done with errors.Is(err,
+// alias), so a comparable sentinel like io.EOF matches by equality and a
+// custom alias matches via err's own Is(error) bool method, without serrors
+// itself walking any further than err's own Unwrap chain. Typical callers
+// register stdlib sentinels such as context.DeadlineExceeded, io.EOF or
+// os.ErrNotExist.
+//
+// Register only ever appends: it does not deduplicate repeat calls for the
+// same alias, nor let a later call override an earlier sentinel for it -
+// resolve just returns the first match, in registration order. Call it from
+// init or an equivalent one-time setup path, not per-request, and register
+// each alias once.
+func Register(sentinel *Error, aliases ...error) {
+	sentinelMappings = append(sentinelMappings, sentinelMapping{sentinel: sentinel, aliases: aliases})
+}
+
+// resolve probes err against the sentinels registered with Register,
+// reporting false if none match.
+func resolve(err error) (*Error, bool) {
+	for _, m := range sentinelMappings {
+		for _, alias := range m.aliases {
+			if errors.Is(err, alias) {
+				return m.sentinel.WithCause(err), true
+			}
+		}
+	}
+	return nil, false
+}
+
 // Clone deep clone error to a new error.
 func Clone(err *Error) *Error {
 	if err == nil {
@@ -101,17 +530,31 @@ func Clone(err *Error) *Error {
 		metadata[k] = v
 	}
 	return &Error{
-		cause: err.cause,
+		causes: err.causes,
+		stack:  err.stack,
 		Status: Status{
 			Errcode: err.Errcode,
 			Errmsg:  err.Errmsg,
 			Result:  metadata,
 		},
+		retryInfo:           err.retryInfo,
+		badRequest:          err.badRequest,
+		quotaFailure:        err.quotaFailure,
+		preconditionFailure: err.preconditionFailure,
+		help:                err.help,
+		localizedMessage:    err.localizedMessage,
+		requestInfo:         err.requestInfo,
 	}
 }
 
 // FromError try to convert an error to *Error.
 // It supports wrapped errors.
+//
+// When err is (or wraps) a gRPC status that carries the chain details added
+// by GRPCStatus, the returned *Error has its cause tree rebuilt so that
+// errors.Is, errors.As and Unwrap behave the same as they did on the server.
+// Otherwise, if err is not a gRPC status either, it is probed against the
+// sentinels declared with Register before falling back to UnknownCode.
 func FromError(err error) *Error {
 	if err == nil {
 		return nil
@@ -121,17 +564,53 @@ func FromError(err error) *Error {
 	}
 	gs, ok := status.FromError(err)
 	if !ok {
+		if se, ok := resolve(err); ok {
+			return se
+		}
 		return New(UnknownCode, err.Error())
 	}
 	ret := New(
 		httpstatus.FromGRPCCode(gs.Code()),
 		gs.Message(),
 	)
+	type causeNode struct {
+		err    *Error
+		parent int
+	}
+	var chain []causeNode
 	for _, detail := range gs.Details() {
 		switch d := detail.(type) {
 		case *errdetails.ErrorInfo:
-			return ret.WithMetadata(d.Metadata)
+			ret = ret.WithMetadata(d.Metadata)
+		case *errdetails.RetryInfo:
+			ret.retryInfo = d
+		case *errdetails.BadRequest:
+			ret.badRequest = d
+		case *errdetails.QuotaFailure:
+			ret.quotaFailure = d
+		case *errdetails.PreconditionFailure:
+			ret.preconditionFailure = d
+		case *errdetails.Help:
+			ret.help = d
+		case *errdetails.LocalizedMessage:
+			ret.localizedMessage = d
+		case *errdetails.RequestInfo:
+			ret.requestInfo = d
+		case *structpb.Struct:
+			if ce, parent, ok := errorFromCauseDetail(d); ok {
+				chain = append(chain, causeNode{ce, parent})
+			}
+		}
+	}
+	if len(chain) > 0 {
+		childrenOf := make(map[int][]error, len(chain))
+		for _, node := range chain {
+			childrenOf[node.parent] = append(childrenOf[node.parent], node.err)
+		}
+		for i, node := range chain {
+			node.err.causes = childrenOf[i]
 		}
+		ret.causes = childrenOf[-1]
 	}
 	return ret
 }
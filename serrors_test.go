@@ -0,0 +1,159 @@
+package serrors
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"os"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcRoundTrip simulates a call crossing gRPC: it encodes err's *status.Status
+// into the error a client would receive; callers decode it with FromError.
+func grpcRoundTrip(err *Error) error {
+	return err.GRPCStatus().Err()
+}
+
+func TestGRPCStatusFromErrorMultiLevelWrapping(t *testing.T) {
+	top := New(400, "bad request").WithCause(
+		New(500, "db down").WithCause(
+			New(503, "pool exhausted"),
+		),
+	)
+
+	got := FromError(grpcRoundTrip(top))
+
+	if got.Errcode != 400 || got.Errmsg != "bad request" {
+		t.Fatalf("top: got code=%d msg=%q, want 400/%q", got.Errcode, got.Errmsg, "bad request")
+	}
+	if len(got.causes) != 1 {
+		t.Fatalf("expected exactly one direct cause, got %d", len(got.causes))
+	}
+	mid, ok := got.causes[0].(*Error)
+	if !ok {
+		t.Fatalf("direct cause is %T, want *Error", got.causes[0])
+	}
+	if mid.Errcode != 500 || mid.Errmsg != "db down" {
+		t.Fatalf("mid: got code=%d msg=%q, want 500/%q", mid.Errcode, mid.Errmsg, "db down")
+	}
+	if len(mid.causes) != 1 {
+		t.Fatalf("expected mid to carry one cause, got %d", len(mid.causes))
+	}
+	leaf, ok := mid.causes[0].(*Error)
+	if !ok {
+		t.Fatalf("leaf cause is %T, want *Error", mid.causes[0])
+	}
+	if leaf.Errcode != 503 || leaf.Errmsg != "pool exhausted" {
+		t.Fatalf("leaf: got code=%d msg=%q, want 503/%q", leaf.Errcode, leaf.Errmsg, "pool exhausted")
+	}
+}
+
+func TestGRPCStatusFromErrorMixedCauses(t *testing.T) {
+	plain := stderrors.New("disk read failed")
+	top := New(500, "internal error").WithCause(plain)
+
+	got := FromError(grpcRoundTrip(top))
+
+	if len(got.causes) != 1 {
+		t.Fatalf("expected one cause, got %d", len(got.causes))
+	}
+	cause, ok := got.causes[0].(*Error)
+	if !ok {
+		t.Fatalf("cause is %T, want *Error", got.causes[0])
+	}
+	if cause.Errcode != UnknownCode {
+		t.Fatalf("non-serrors cause got code %d, want UnknownCode", cause.Errcode)
+	}
+	if cause.Errmsg != plain.Error() {
+		t.Fatalf("non-serrors cause got msg %q, want %q", cause.Errmsg, plain.Error())
+	}
+}
+
+func TestFromErrorBareStatus(t *testing.T) {
+	s := status.New(codes.NotFound, "no such user")
+
+	got := FromError(s.Err())
+
+	if got.Errmsg != "no such user" {
+		t.Fatalf("got msg %q, want %q", got.Errmsg, "no such user")
+	}
+	if got.Errcode != 404 {
+		t.Fatalf("got code %d, want 404", got.Errcode)
+	}
+	if len(got.causes) != 0 {
+		t.Fatalf("bare status should have no reconstructed causes, got %d", len(got.causes))
+	}
+}
+
+// withCleanSentinels snapshots sentinelMappings and restores it on cleanup,
+// so a test that calls Register doesn't leak aliases into tests that run
+// after it.
+func withCleanSentinels(t *testing.T) {
+	t.Helper()
+	saved := sentinelMappings
+	t.Cleanup(func() { sentinelMappings = saved })
+}
+
+func TestFromErrorRegisteredStdlibSentinels(t *testing.T) {
+	withCleanSentinels(t)
+	deadline := New(504, "deadline exceeded")
+	eof := New(499, "stream closed")
+	Register(deadline, context.DeadlineExceeded)
+	Register(eof, io.EOF, os.ErrNotExist)
+
+	for _, tc := range []struct {
+		name string
+		err  error
+		want int32
+	}{
+		{"deadline", context.DeadlineExceeded, 504},
+		{"eof", io.EOF, 499},
+		{"not exist", os.ErrNotExist, 499},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := FromError(tc.err)
+			if got.Errcode != tc.want {
+				t.Fatalf("got code %d, want %d", got.Errcode, tc.want)
+			}
+			if len(got.causes) != 1 || got.causes[0] != tc.err {
+				t.Fatalf("expected %v as the sole cause, got %v", tc.err, got.causes)
+			}
+		})
+	}
+}
+
+// isOnlyError implements Is(error) bool but not Unwrap() error, the shape
+// Register/resolve must support without trying to unwrap any further.
+type isOnlyError struct {
+	msg    string
+	target error
+}
+
+func (e *isOnlyError) Error() string        { return e.msg }
+func (e *isOnlyError) Is(target error) bool { return target == e.target }
+
+func TestResolveIsOnlyNoUnwrap(t *testing.T) {
+	withCleanSentinels(t)
+	marker := stderrors.New("validation marker")
+	sentinel := New(422, "validation failed")
+	Register(sentinel, marker)
+
+	got := FromError(&isOnlyError{msg: "field required", target: marker})
+	if got.Errcode != 422 {
+		t.Fatalf("got code %d, want 422", got.Errcode)
+	}
+}
+
+func TestFromErrorNilAndPlainError(t *testing.T) {
+	if FromError(nil) != nil {
+		t.Fatal("FromError(nil) should be nil")
+	}
+	plain := stderrors.New("boom")
+	got := FromError(plain)
+	if got.Errcode != UnknownCode || got.Errmsg != "boom" {
+		t.Fatalf("got code=%d msg=%q, want UnknownCode/%q", got.Errcode, got.Errmsg, "boom")
+	}
+}
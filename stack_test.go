@@ -0,0 +1,79 @@
+package serrors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStackTraceFiltersPackageFrames(t *testing.T) {
+	err := New(500, "boom")
+
+	frames := err.StackTrace()
+	if len(frames) == 0 {
+		t.Fatal("expected at least one captured frame")
+	}
+	for _, f := range frames {
+		if f.File == pkgFile {
+			t.Fatalf("frame %+v belongs to this package and should have been filtered", f)
+		}
+	}
+	if frames[0].Function == "" || !strings.Contains(frames[0].Function, "TestStackTraceFiltersPackageFrames") {
+		t.Fatalf("expected the top frame to be the test function, got %q", frames[0].Function)
+	}
+}
+
+func TestStackTraceDisabled(t *testing.T) {
+	SetCaptureStack(false)
+	defer SetCaptureStack(true)
+
+	err := New(500, "boom")
+	if frames := err.StackTrace(); frames != nil {
+		t.Fatalf("expected no captured stack, got %d frames", len(frames))
+	}
+}
+
+func TestFormatPlusVIncludesStackPlainVDoesNot(t *testing.T) {
+	err := New(500, "boom")
+
+	plain := fmt.Sprintf("%v", err)
+	if plain != err.Error() {
+		t.Fatalf("%%v got %q, want %q", plain, err.Error())
+	}
+	if strings.Contains(plain, pkgFile) {
+		t.Fatal("plain verb formatting should not print a stack trace")
+	}
+
+	detailed := fmt.Sprintf("%+v", err)
+	if !strings.HasPrefix(detailed, err.Error()) {
+		t.Fatalf("%%+v should start with the error message, got %q", detailed)
+	}
+	for _, fr := range err.StackTrace() {
+		if !strings.Contains(detailed, fr.Function) {
+			t.Fatalf("%%+v output missing frame %q:\n%s", fr.Function, detailed)
+		}
+	}
+}
+
+func TestCloneAndWithCauseStackCapture(t *testing.T) {
+	original := New(500, "boom")
+
+	cloned := Clone(original)
+	if len(cloned.stack) != len(original.stack) {
+		t.Fatalf("Clone should preserve the original stack, got %d frames vs %d", len(cloned.stack), len(original.stack))
+	}
+	for i, pc := range original.stack {
+		if cloned.stack[i] != pc {
+			t.Fatalf("Clone's stack diverges from the original at frame %d", i)
+		}
+	}
+
+	wrapped := original.WithCause(fmt.Errorf("underlying failure"))
+	if len(wrapped.StackTrace()) == 0 {
+		t.Fatal("WithCause should capture a stack")
+	}
+	if wrapped.StackTrace()[0].Function == original.StackTrace()[0].Function &&
+		wrapped.StackTrace()[0].Line == original.StackTrace()[0].Line {
+		t.Fatal("WithCause should capture a fresh stack at its own call site, not reuse the original")
+	}
+}